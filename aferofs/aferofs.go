@@ -0,0 +1,56 @@
+// Package aferofs adapts an afero.Fs into the livefile.WriteFS interface so
+// that any afero-backed filesystem (MemMapFs, BasePathFs, CacheOnReadFs, ...)
+// can be used as the backing store for a [livefile.LiveFile].
+package aferofs
+
+import (
+	"io/fs"
+
+	"github.com/spf13/afero"
+	"github.com/vvoland/livefile"
+)
+
+// FS implements livefile.WriteFS on top of an afero.Fs.
+type FS struct {
+	fs afero.Fs
+}
+
+// New wraps fs so it can be passed to livefile.WithFileSystem.
+func New(fs afero.Fs) *FS {
+	return &FS{fs: fs}
+}
+
+// Unwrap returns the underlying afero.Fs.
+func (a *FS) Unwrap() afero.Fs {
+	return a.fs
+}
+
+// Open implements livefile.WriteFS.
+func (a *FS) Open(name string) (livefile.ReadSeekFile, error) {
+	return a.fs.Open(name)
+}
+
+// OpenFile implements livefile.WriteFS.
+func (a *FS) OpenFile(name string, flag int, perm fs.FileMode) (livefile.WriteFile, error) {
+	return a.fs.OpenFile(name, flag, perm)
+}
+
+// MkdirAll implements livefile.WriteFS.
+func (a *FS) MkdirAll(path string, perm fs.FileMode) error {
+	return a.fs.MkdirAll(path, perm)
+}
+
+// Remove implements livefile.WriteFS.
+func (a *FS) Remove(name string) error {
+	return a.fs.Remove(name)
+}
+
+// Rename implements livefile.WriteFS.
+func (a *FS) Rename(oldName, newName string) error {
+	return a.fs.Rename(oldName, newName)
+}
+
+// Glob implements livefile.DirLister.
+func (a *FS) Glob(pattern string) ([]string, error) {
+	return afero.Glob(a.fs, pattern)
+}
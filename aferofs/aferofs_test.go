@@ -0,0 +1,58 @@
+package aferofs_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/vvoland/livefile"
+	"github.com/vvoland/livefile/aferofs"
+	"gotest.tools/assert"
+	"gotest.tools/assert/cmp"
+)
+
+type testData struct {
+	Value int
+	Name  string
+}
+
+func TestLiveFileOnMemMapFs(t *testing.T) {
+	ctx := context.Background()
+	fs := aferofs.New(afero.NewMemMapFs())
+
+	f := livefile.New("/state/data.json", livefile.WithFileSystem[testData](fs),
+		livefile.WithDefault(func() testData {
+			return testData{Value: 42, Name: "test"}
+		}))
+
+	t.Run("Peek uses the default before any write", func(t *testing.T) {
+		data := f.Peek(ctx)
+		assert.Check(t, cmp.Equal(data.Value, 42))
+		assert.Check(t, cmp.Equal(data.Name, "test"))
+	})
+
+	t.Run("Update persists through the afero filesystem", func(t *testing.T) {
+		err := f.Update(ctx, func(data *testData) error {
+			data.Value = 100
+			data.Name = "updated"
+			return nil
+		})
+		assert.NilError(t, err)
+
+		data := f.Peek(ctx)
+		assert.Check(t, cmp.Equal(data.Value, 100))
+		assert.Check(t, cmp.Equal(data.Name, "updated"))
+
+		raw, err := afero.ReadFile(fs.Unwrap(), "/state/data.json")
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Contains(string(raw), `"Value": 100`))
+	})
+
+	t.Run("external change through the afero Fs is picked up", func(t *testing.T) {
+		assert.NilError(t, afero.WriteFile(fs.Unwrap(), "/state/data.json", []byte(`{"Value": 1337, "Name": "foobar"}`), 0o600))
+
+		data := f.Peek(ctx)
+		assert.Check(t, cmp.Equal(data.Value, 1337))
+		assert.Check(t, cmp.Equal(data.Name, "foobar"))
+	})
+}
@@ -0,0 +1,112 @@
+package livefile
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+	"gotest.tools/assert/cmp"
+)
+
+func TestAtomicWriteRoundTrip(t *testing.T) {
+	path := testFilePath(t)
+	ctx := context.Background()
+
+	f := New(path, WithDefault(func() TestData {
+		return TestData{Value: 42, Name: "test"}
+	}), WithAtomicWrite[TestData](true))
+
+	err := f.Update(ctx, func(data *TestData) error {
+		data.Value = 5
+		return nil
+	})
+	assert.NilError(t, err)
+
+	data := f.Peek(ctx)
+	assert.Check(t, cmp.Equal(data.Value, 5))
+
+	matches, err := filepath.Glob(path + tempFileSuffix + "*")
+	assert.NilError(t, err)
+	assert.Check(t, cmp.Len(matches, 0))
+}
+
+func TestAtomicWriteLeavesOriginalUntouchedOnEncodeFailure(t *testing.T) {
+	path := testFilePath(t)
+	ctx := context.Background()
+
+	type unencodable struct {
+		Ch chan int
+	}
+
+	f := New(path, WithDefault(func() unencodable {
+		return unencodable{}
+	}), WithAtomicWrite[unencodable](true))
+
+	// Seed an initial file directly: unencodable can never be produced by a
+	// successful Update, but it decodes fine from "{}".
+	assert.NilError(t, os.WriteFile(path, []byte(`{}`), 0o600))
+	original, err := os.ReadFile(path)
+	assert.NilError(t, err)
+
+	err = f.Update(ctx, func(data *unencodable) error {
+		data.Ch = make(chan int)
+		return nil
+	})
+	assert.Check(t, err != nil)
+
+	after, err := os.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Check(t, cmp.DeepEqual(original, after))
+
+	matches, err := filepath.Glob(path + tempFileSuffix + "*")
+	assert.NilError(t, err)
+	assert.Check(t, cmp.Len(matches, 0))
+}
+
+// TestAtomicWriteLeavesNoFileOnEncodeFailureForFreshPath proves that a failed
+// atomic-mode Update on a path that doesn't exist yet leaves no file behind
+// at all - in particular, Update must not create an empty lf.path as a
+// side effect of a stale pre-write open it no longer needs.
+func TestAtomicWriteLeavesNoFileOnEncodeFailureForFreshPath(t *testing.T) {
+	path := testFilePath(t)
+	ctx := context.Background()
+
+	type unencodable struct {
+		Ch chan int
+	}
+
+	f := New(path, WithDefault(func() unencodable {
+		return unencodable{}
+	}), WithAtomicWrite[unencodable](true))
+
+	err := f.Update(ctx, func(data *unencodable) error {
+		data.Ch = make(chan int)
+		return nil
+	})
+	assert.Check(t, err != nil)
+
+	_, err = os.Stat(path)
+	assert.Check(t, os.IsNotExist(err))
+
+	matches, err := filepath.Glob(path + tempFileSuffix + "*")
+	assert.NilError(t, err)
+	assert.Check(t, cmp.Len(matches, 0))
+}
+
+func TestNewRemovesStrayAtomicTempFiles(t *testing.T) {
+	path := testFilePath(t)
+
+	assert.NilError(t, os.WriteFile(path, []byte(`{"Value": 1, "Name": "a"}`), 0o600))
+	stray := fmt.Sprintf("%s%s%d-1", path, tempFileSuffix, os.Getpid())
+	assert.NilError(t, os.WriteFile(stray, []byte("leftover"), 0o600))
+
+	New(path, WithDefault(func() TestData {
+		return TestData{}
+	}), WithAtomicWrite[TestData](true))
+
+	_, err := os.Stat(stray)
+	assert.Check(t, os.IsNotExist(err))
+}
@@ -0,0 +1,26 @@
+package livefile
+
+// ChangeDetectionMode controls how a [LiveFile] decides that the file on
+// disk has been modified by something other than itself and needs to be
+// reloaded.
+type ChangeDetectionMode int
+
+const (
+	// ModeMtime reloads the file only when its modification time has
+	// advanced past the last load. This is the default: it is the cheapest
+	// check, but it can miss an external write that lands within the same
+	// mtime granularity as the last load (often 1 second) or that restores
+	// an earlier mtime (e.g. via os.Chtimes).
+	ModeMtime ChangeDetectionMode = iota
+
+	// ModeSizeHash ignores the modification time entirely and instead keeps
+	// a size+hash fingerprint of the file contents computed at load time.
+	// The hash is only recomputed when the file size still matches the
+	// known size; a size mismatch is treated as a change without hashing.
+	ModeSizeHash
+
+	// ModeSizeMtimeHash behaves like ModeSizeHash but also reloads whenever
+	// the modification time advances, so an mtime-only change (e.g. a
+	// same-content rewrite) still triggers the [WithLoadedCallback] hook.
+	ModeSizeMtimeHash
+)
@@ -4,6 +4,10 @@ import (
 	"io"
 	"io/fs"
 	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/fsnotify/fsnotify"
 )
 
 type WriteFS interface {
@@ -11,6 +15,7 @@ type WriteFS interface {
 	OpenFile(name string, flag int, perm fs.FileMode) (WriteFile, error)
 	MkdirAll(path string, perm fs.FileMode) error
 	Remove(name string) error
+	Rename(oldName, newName string) error
 }
 
 type WriteFile interface {
@@ -26,6 +31,50 @@ type ReadSeekFile interface {
 	io.Seeker
 }
 
+// Watcher is an optional capability a [WriteFS] implementation may provide
+// to receive native filesystem change notifications for a directory.
+// [LiveFile.Subscribe] uses it when available, falling back to polling
+// otherwise.
+type Watcher interface {
+	// Watch starts watching dir for changes and returns a channel of events
+	// for it, plus a function that stops the watch and closes the channel.
+	Watch(dir string) (<-chan WatchEvent, func() error, error)
+}
+
+// WatchOp describes the kind of filesystem event a [WatchEvent] represents.
+type WatchOp int
+
+const (
+	WatchWrite WatchOp = iota
+	WatchCreate
+	WatchRename
+	WatchRemove
+)
+
+// WatchEvent is a single filesystem change notification for a file within a
+// watched directory.
+type WatchEvent struct {
+	// Name is the path of the entry the event is about, as reported by the
+	// underlying filesystem. It may be relative to the watched directory.
+	Name string
+	Op   WatchOp
+}
+
+// DirSyncer is an optional capability a [WriteFS] implementation may provide
+// to fsync a directory entry, making a preceding [WriteFS.Rename] durable on
+// POSIX filesystems. Filesystems without a real directory to sync (e.g.
+// in-memory ones) can simply not implement it.
+type DirSyncer interface {
+	SyncDir(dir string) error
+}
+
+// DirLister is an optional capability a [WriteFS] implementation may provide
+// to list files matching a glob pattern. [New] uses it, when available, to
+// remove stray temporary files left behind by [WithAtomicWrite].
+type DirLister interface {
+	Glob(pattern string) ([]string, error)
+}
+
 // osFileSystem implements WriteFS using the os package
 type osFileSystem struct{}
 
@@ -48,3 +97,92 @@ func (osfs osFileSystem) MkdirAll(path string, perm fs.FileMode) error {
 func (osfs osFileSystem) Remove(name string) error {
 	return os.Remove(name)
 }
+
+func (osfs osFileSystem) Rename(oldName, newName string) error {
+	return os.Rename(oldName, newName)
+}
+
+// SyncDir implements [DirSyncer]. It is a no-op on Windows, where directory
+// handles can't be opened for syncing.
+func (osfs osFileSystem) SyncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return f.Sync()
+}
+
+// Glob implements [DirLister].
+func (osfs osFileSystem) Glob(pattern string) ([]string, error) {
+	return filepath.Glob(pattern)
+}
+
+// Watch implements [Watcher] using fsnotify. It watches the directory
+// itself rather than the target file, so the watch survives the file being
+// atomically replaced (a rename or remove of the file doesn't invalidate a
+// directory watch the way it would a watch on the file's own inode).
+func (osfs osFileSystem) Watch(dir string) (<-chan WatchEvent, func() error, error) {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := w.Add(dir); err != nil {
+		w.Close()
+		return nil, nil, err
+	}
+
+	events := make(chan WatchEvent)
+	done := make(chan struct{})
+	go func() {
+		defer close(events)
+		for {
+			select {
+			case ev, ok := <-w.Events:
+				if !ok {
+					return
+				}
+				op, ok := translateWatchOp(ev.Op)
+				if !ok {
+					continue
+				}
+				select {
+				case events <- WatchEvent{Name: ev.Name, Op: op}:
+				case <-done:
+					return
+				}
+			case _, ok := <-w.Errors:
+				if !ok {
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	stop := func() error {
+		close(done)
+		return w.Close()
+	}
+	return events, stop, nil
+}
+
+func translateWatchOp(op fsnotify.Op) (WatchOp, bool) {
+	switch {
+	case op&fsnotify.Write != 0:
+		return WatchWrite, true
+	case op&fsnotify.Create != 0:
+		return WatchCreate, true
+	case op&fsnotify.Rename != 0:
+		return WatchRename, true
+	case op&fsnotify.Remove != 0:
+		return WatchRemove, true
+	default:
+		return 0, false
+	}
+}
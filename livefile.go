@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"io"
 	"io/fs"
+	"math/rand"
 	"os"
 	"path"
 	"path/filepath"
@@ -14,6 +16,10 @@ import (
 	"time"
 )
 
+// tempFileSuffix marks the temporary files written by [WithAtomicWrite]
+// before they are renamed over the destination, e.g. "data.json.tmp-123-456".
+const tempFileSuffix = ".tmp-"
+
 type LiveFile[StateT any] struct {
 	path string
 	fs   WriteFS
@@ -25,6 +31,17 @@ type LiveFile[StateT any] struct {
 	defaultFunc func() StateT
 	errHandler  func(context.Context, error)
 	onLoaded    func(context.Context, *StateT)
+
+	writeBackDelay time.Duration
+	flushTimer     *time.Timer
+	flushPending   bool
+
+	changeMode ChangeDetectionMode
+	fpSize     int64
+	fpHash     uint64
+	fpSet      bool
+
+	atomicWrite bool
 }
 
 // DefaultErrorHandler is the default error handler used for all [LiveFile]
@@ -64,9 +81,32 @@ func New[T any](path string, opts ...Opt[T]) *LiveFile[T] {
 		}
 	}
 	lf.cached = lf.defaultFunc()
+
+	if lf.atomicWrite {
+		lf.removeStrayTempFiles()
+	}
 	return lf
 }
 
+// removeStrayTempFiles best-effort removes leftover "<path>.tmp-*" files
+// from a previous process that crashed between creating its temp file and
+// renaming it over lf.path. It is a no-op if lf.fs doesn't implement
+// [DirLister].
+func (lf *LiveFile[T]) removeStrayTempFiles() {
+	lister, ok := lf.fs.(DirLister)
+	if !ok {
+		return
+	}
+
+	matches, err := lister.Glob(lf.path + tempFileSuffix + "*")
+	if err != nil {
+		return
+	}
+	for _, stray := range matches {
+		lf.fs.Remove(stray)
+	}
+}
+
 // View retrieves the current state of the file and passes it to the given
 // function. The state pointer is only valid within the function call and
 // must not be stored.
@@ -83,20 +123,32 @@ func (lf *LiveFile[T]) View(ctx context.Context, f func(state *T)) {
 // state. If the function returns an error, the state is rolled back to the
 // previous value.
 // The function MUST NOT call other [LiveFile] methods.
+//
+// If [WithWriteBack] was used, a successful call only updates the in-memory
+// state and schedules a deferred flush to disk; use [LiveFile.Flush] to force
+// a synchronous write.
 func (lf *LiveFile[T]) Update(ctx context.Context, f func(state *T) error) error {
 	lf.mutex.Lock()
 	defer lf.mutex.Unlock()
 
 	lf.ensure(ctx)
 
-	file, err := lf.fs.OpenFile(lf.path, os.O_RDWR|os.O_CREATE, 0o660)
-	if errors.Is(err, fs.ErrNotExist) {
-		err = lf.fs.MkdirAll(path.Dir(lf.path), 0o770)
-		if err != nil {
+	if lf.writeBackDelay > 0 {
+		if err := lf.applyOrRollback(f); err != nil {
 			return err
 		}
-		file, err = lf.fs.OpenFile(lf.path, os.O_RDWR|os.O_CREATE, 0o660)
+		lf.schedulePendingFlush(ctx)
+		return nil
 	}
+
+	if lf.atomicWrite {
+		if err := lf.applyOrRollback(f); err != nil {
+			return err
+		}
+		return lf.writeAtomic(ctx)
+	}
+
+	file, err := lf.openForWrite()
 	if err != nil {
 		return err
 	}
@@ -110,10 +162,123 @@ func (lf *LiveFile[T]) Update(ctx context.Context, f func(state *T) error) error
 		return err
 	}
 
-	err = file.Truncate(0)
+	return lf.writeToFile(file)
+}
+
+// applyOrRollback runs f against lf.cached in place, restoring the previous
+// value if f returns an error. Used by the write-back and atomic-write
+// branches of Update, which mutate lf.cached directly instead of rolling
+// back from a freshly reread file.
+func (lf *LiveFile[T]) applyOrRollback(f func(state *T) error) error {
+	prev := lf.cached
+	if err := f(&lf.cached); err != nil {
+		lf.cached = prev
+		return err
+	}
+	return nil
+}
+
+// Flush writes the pending in-memory state deferred by [WithWriteBack] to disk
+// immediately, cancelling the scheduled deferred flush. It is a no-op if
+// nothing is pending, which is always the case when write-back mode is
+// disabled.
+func (lf *LiveFile[T]) Flush(ctx context.Context) error {
+	lf.mutex.Lock()
+	defer lf.mutex.Unlock()
+
+	return lf.flushLocked(ctx)
+}
+
+// Close cancels any pending deferred flush and guarantees that the current
+// in-memory state has been made durable. It is safe to call even if
+// [WithWriteBack] was not used.
+func (lf *LiveFile[T]) Close(ctx context.Context) error {
+	lf.mutex.Lock()
+	defer lf.mutex.Unlock()
+
+	if lf.flushTimer == nil && !lf.flushPending {
+		return nil
+	}
+	return lf.flushLocked(ctx)
+}
+
+// schedulePendingFlush marks the state as pending and arms the deferred flush
+// timer if one isn't already armed. Must be called with lf.mutex held.
+func (lf *LiveFile[T]) schedulePendingFlush(ctx context.Context) {
+	if lf.flushTimer != nil {
+		return
+	}
+	lf.flushPending = true
+	lf.flushTimer = time.AfterFunc(lf.writeBackDelay, func() {
+		lf.mutex.Lock()
+		defer lf.mutex.Unlock()
+
+		if !lf.flushPending {
+			return
+		}
+		if err := lf.flushLocked(ctx); err != nil {
+			lf.errHandler(ctx, err)
+		}
+	})
+}
+
+// flushLocked writes the current in-memory state to disk. Must be called
+// with lf.mutex held. If the write fails, the state stays pending so that a
+// later Flush, Close or Update retries it.
+func (lf *LiveFile[T]) flushLocked(ctx context.Context) (err error) {
+	if lf.flushTimer != nil {
+		lf.flushTimer.Stop()
+		lf.flushTimer = nil
+	}
+	if !lf.flushPending {
+		return nil
+	}
+	lf.flushPending = false
+	defer func() {
+		if err != nil {
+			lf.flushPending = true
+		}
+	}()
+
+	if lf.atomicWrite {
+		return lf.writeAtomic(ctx)
+	}
+
+	file, err := lf.openForWrite()
 	if err != nil {
 		return err
 	}
+	defer file.Close()
+
+	return lf.writeToFile(file)
+}
+
+// openForWrite opens lf.path for read-write, creating the file and its parent
+// directory if necessary.
+func (lf *LiveFile[T]) openForWrite() (WriteFile, error) {
+	file, err := lf.fs.OpenFile(lf.path, os.O_RDWR|os.O_CREATE, 0o660)
+	if errors.Is(err, fs.ErrNotExist) {
+		err = lf.fs.MkdirAll(path.Dir(lf.path), 0o770)
+		if err != nil {
+			return nil, err
+		}
+		file, err = lf.fs.OpenFile(lf.path, os.O_RDWR|os.O_CREATE, 0o660)
+	}
+	return file, err
+}
+
+// writeToFile encodes the current in-memory state into file and records its
+// new modification time.
+func (lf *LiveFile[T]) writeToFile(file WriteFile) error {
+	err := file.Truncate(0)
+	if err != nil {
+		return err
+	}
+	// Truncate doesn't move the offset, which a prior read (e.g. fingerprint)
+	// may have left at the old EOF.
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
 
 	enc := json.NewEncoder(file)
 	enc.SetIndent("", "  ")
@@ -131,6 +296,64 @@ func (lf *LiveFile[T]) Update(ctx context.Context, f func(state *T) error) error
 	stat, err := file.Stat()
 	if err == nil {
 		lf.lastModTime = stat.ModTime()
+		lf.refreshFingerprint(file, stat)
+	}
+	return err
+}
+
+// writeAtomic encodes the current in-memory state into a temp file beside
+// lf.path, syncs it, and renames it over lf.path, so a crash or a panicking
+// [WithErrorHandler] mid-encode can never leave a half-written destination
+// behind. Unlike [LiveFile.writeToFile] it never touches lf.path directly
+// until the rename, and only opens it afterwards (via [WriteFS.Open]) to
+// record the fresh mtime and fingerprint - callers must not pre-open
+// lf.path for it.
+func (lf *LiveFile[T]) writeAtomic(ctx context.Context) error {
+	tmpPath := fmt.Sprintf("%s%s%d-%d", lf.path, tempFileSuffix, os.Getpid(), rand.Int63())
+
+	tmp, err := lf.fs.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o660)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(tmp)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(lf.cached); err != nil {
+		tmp.Close()
+		lf.fs.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		lf.fs.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		lf.fs.Remove(tmpPath)
+		return err
+	}
+
+	if err := lf.fs.Rename(tmpPath, lf.path); err != nil {
+		lf.fs.Remove(tmpPath)
+		return err
+	}
+
+	if syncer, ok := lf.fs.(DirSyncer); ok {
+		if err := syncer.SyncDir(path.Dir(lf.path)); err != nil {
+			return err
+		}
+	}
+
+	file, err := lf.fs.Open(lf.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err == nil {
+		lf.lastModTime = stat.ModTime()
+		lf.refreshFingerprint(file, stat)
 	}
 	return err
 }
@@ -144,33 +367,109 @@ func (lf *LiveFile[T]) Peek(ctx context.Context) T {
 	return c
 }
 
-func (lf *LiveFile[T]) ensure(ctx context.Context) {
+// ensure reloads lf.cached from disk if it looks stale, reporting whether a
+// reload actually happened. It is a no-op while a write-back flush is still
+// pending: reloading then would discard the unflushed mutation in favor of
+// whatever currently happens to be on disk, which defeats the point of
+// coalescing writes. The pending in-memory state wins and is made durable by
+// the scheduled flush instead.
+func (lf *LiveFile[T]) ensure(ctx context.Context) bool {
+	if lf.flushPending {
+		return false
+	}
+
 	file, err := lf.fs.Open(lf.path)
 	if err != nil {
 		if !errors.Is(err, fs.ErrNotExist) {
 			lf.errHandler(ctx, err)
 		}
-	} else {
-		lf.loadIfUpdated(ctx, file)
-		file.Close()
+		return false
 	}
+	defer file.Close()
+
+	return lf.loadIfUpdated(ctx, file)
 }
 
-func (lf *LiveFile[T]) loadIfUpdated(ctx context.Context, file ReadSeekFile) {
+// loadIfUpdated reloads lf.cached from file if it looks stale according to
+// lf.changeMode, reporting whether a reload actually happened. Callers that
+// need to know whether lf.cached changed (e.g. [LiveFile.reloadAndNotify])
+// must use this return value instead of comparing lastModTime themselves,
+// since a reload can also be triggered purely by the size+hash fingerprint
+// (see [ModeSizeHash]) without lastModTime advancing at all.
+func (lf *LiveFile[T]) loadIfUpdated(ctx context.Context, file ReadSeekFile) bool {
 	stat, err := file.Stat()
 	if err != nil {
 		lf.errHandler(ctx, fmt.Errorf("stat failed: %w", err))
 	}
 
 	if stat.Size() == 0 {
+		return false
+	}
+
+	stale, err := lf.isStale(file, stat)
+	if err != nil {
+		lf.errHandler(ctx, fmt.Errorf("fingerprint failed: %w", err))
+	}
+	if !stale {
+		return false
+	}
+
+	lf.forceLoad(ctx, file)
+	lf.lastModTime = stat.ModTime()
+	lf.refreshFingerprint(file, stat)
+	return true
+}
+
+// isStale reports whether file looks different from what was last loaded,
+// according to lf.changeMode.
+func (lf *LiveFile[T]) isStale(file ReadSeekFile, stat fs.FileInfo) (bool, error) {
+	if lf.changeMode == ModeMtime {
+		return stat.ModTime().After(lf.lastModTime), nil
+	}
+
+	if lf.changeMode == ModeSizeMtimeHash && stat.ModTime().After(lf.lastModTime) {
+		return true, nil
+	}
+	if !lf.fpSet || stat.Size() != lf.fpSize {
+		return true, nil
+	}
+
+	_, hash, err := fingerprint(file)
+	if err != nil {
+		return false, err
+	}
+	return hash != lf.fpHash, nil
+}
+
+// refreshFingerprint recomputes and stores the size+hash fingerprint of file,
+// unless lf.changeMode is ModeMtime where it isn't needed.
+func (lf *LiveFile[T]) refreshFingerprint(file ReadSeekFile, stat fs.FileInfo) {
+	if lf.changeMode == ModeMtime {
 		return
 	}
 
-	modTime := stat.ModTime()
-	if modTime.After(lf.lastModTime) {
-		lf.forceLoad(ctx, file)
-		lf.lastModTime = modTime
+	size, hash, err := fingerprint(file)
+	if err != nil {
+		return
+	}
+	lf.fpSize = size
+	lf.fpHash = hash
+	lf.fpSet = true
+}
+
+// fingerprint computes a cheap size+hash fingerprint of file's contents,
+// leaving the read position at EOF.
+func fingerprint(file ReadSeekFile) (size int64, hash uint64, err error) {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return 0, 0, err
+	}
+
+	h := fnv.New64a()
+	size, err = io.Copy(h, file)
+	if err != nil {
+		return 0, 0, err
 	}
+	return size, h.Sum64(), nil
 }
 
 func (lf *LiveFile[T]) forceLoad(ctx context.Context, file ReadSeekFile) {
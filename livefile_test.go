@@ -0,0 +1,258 @@
+package livefile
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	"gotest.tools/assert/cmp"
+)
+
+func testFilePath(t *testing.T) string {
+	dir := t.TempDir()
+	assert.NilError(t, os.MkdirAll(dir, 0o700))
+	return filepath.Join(dir, "testfile.json")
+}
+
+type TestData struct {
+	Value int
+	Name  string
+}
+
+func TestWriteBackCoalescesUpdates(t *testing.T) {
+	path := testFilePath(t)
+	ctx := context.Background()
+
+	f := New(path, WithDefault(func() TestData {
+		return TestData{Value: 42, Name: "test"}
+	}), WithWriteBack[TestData](time.Hour))
+
+	err := f.Update(ctx, func(data *TestData) error {
+		data.Value = 1
+		return nil
+	})
+	assert.NilError(t, err)
+	err = f.Update(ctx, func(data *TestData) error {
+		data.Value = 2
+		return nil
+	})
+	assert.NilError(t, err)
+
+	// Nothing should have reached disk yet.
+	_, err = os.Stat(path)
+	assert.Check(t, os.IsNotExist(err))
+
+	// View/Peek must see the pending in-memory value.
+	data := f.Peek(ctx)
+	assert.Check(t, cmp.Equal(data.Value, 2))
+
+	assert.NilError(t, f.Flush(ctx))
+	raw, err := os.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Check(t, cmp.Contains(string(raw), `"Value": 2`))
+}
+
+func TestWriteBackCloseFlushesPendingState(t *testing.T) {
+	path := testFilePath(t)
+	ctx := context.Background()
+
+	f := New(path, WithDefault(func() TestData {
+		return TestData{Value: 42, Name: "test"}
+	}), WithWriteBack[TestData](time.Hour))
+
+	err := f.Update(ctx, func(data *TestData) error {
+		data.Value = 7
+		return nil
+	})
+	assert.NilError(t, err)
+
+	assert.NilError(t, f.Close(ctx))
+	raw, err := os.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Check(t, cmp.Contains(string(raw), `"Value": 7`))
+}
+
+func TestWriteBackDeferredFlushHappensAfterDelay(t *testing.T) {
+	path := testFilePath(t)
+	ctx := context.Background()
+
+	f := New(path, WithDefault(func() TestData {
+		return TestData{Value: 42, Name: "test"}
+	}), WithWriteBack[TestData](10*time.Millisecond))
+
+	err := f.Update(ctx, func(data *TestData) error {
+		data.Value = 9
+		return nil
+	})
+	assert.NilError(t, err)
+
+	time.Sleep(100 * time.Millisecond)
+
+	raw, err := os.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Check(t, cmp.Contains(string(raw), `"Value": 9`))
+}
+
+// TestWriteBackPendingFlushSurvivesExternalWrite proves that an external
+// write landing during the coalescing window doesn't get pulled into
+// lf.cached and silently overwrite the not-yet-flushed mutation.
+func TestWriteBackPendingFlushSurvivesExternalWrite(t *testing.T) {
+	path := testFilePath(t)
+	ctx := context.Background()
+
+	f := New(path, WithDefault(func() TestData {
+		return TestData{Value: 42, Name: "test"}
+	}), WithWriteBack[TestData](time.Hour))
+
+	err := f.Update(ctx, func(data *TestData) error {
+		data.Value = 1
+		return nil
+	})
+	assert.NilError(t, err)
+
+	// An external writer lands while our flush is still pending.
+	assert.NilError(t, os.WriteFile(path, []byte(`{"Value": 99, "Name": "external"}`), 0o600))
+
+	data := f.Peek(ctx)
+	assert.Check(t, cmp.Equal(data.Value, 1))
+
+	err = f.Update(ctx, func(data *TestData) error {
+		data.Value = 2
+		return nil
+	})
+	assert.NilError(t, err)
+
+	assert.NilError(t, f.Flush(ctx))
+	raw, err := os.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Check(t, cmp.Contains(string(raw), `"Value": 2`))
+}
+
+// failingWriteFS wraps a WriteFS but fails every OpenFile call, simulating a
+// backing store that can no longer be written to.
+type failingWriteFS struct {
+	WriteFS
+}
+
+var errInjectedOpenFailure = errors.New("injected open failure")
+
+func (failingWriteFS) OpenFile(name string, flag int, perm fs.FileMode) (WriteFile, error) {
+	return nil, errInjectedOpenFailure
+}
+
+// TestWriteBackFlushFailureReportedToErrorHandler proves that a background
+// flush failure reaches the configured error handler instead of being
+// silently dropped.
+func TestWriteBackFlushFailureReportedToErrorHandler(t *testing.T) {
+	path := testFilePath(t)
+	ctx := context.Background()
+
+	errs := make(chan error, 1)
+	f := New(path, WithDefault(func() TestData {
+		return TestData{Value: 42, Name: "test"}
+	}),
+		WithWriteBack[TestData](10*time.Millisecond),
+		WithErrorHandler[TestData](func(_ context.Context, err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		}))
+	f.fs = failingWriteFS{WriteFS: f.fs}
+
+	err := f.Update(ctx, func(data *TestData) error {
+		data.Value = 1
+		return nil
+	})
+	assert.NilError(t, err)
+
+	select {
+	case err := <-errs:
+		assert.Check(t, errors.Is(err, errInjectedOpenFailure))
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the background flush failure to be reported")
+	}
+}
+
+// TestChangeDetectionSizeHashCatchesSameMtimeWrite reproduces an external
+// write that lands with the same size and the same (forced) mtime as the
+// last load, which a pure [ModeMtime] check would miss.
+func TestChangeDetectionSizeHashCatchesSameMtimeWrite(t *testing.T) {
+	path := testFilePath(t)
+	ctx := context.Background()
+
+	f := New(path, WithDefault(func() TestData {
+		return TestData{Value: 42, Name: "test"}
+	}), WithChangeDetection[TestData](ModeSizeHash))
+
+	err := f.Update(ctx, func(data *TestData) error {
+		data.Value = 1
+		data.Name = "test"
+		return nil
+	})
+	assert.NilError(t, err)
+
+	info, err := os.Stat(path)
+	assert.NilError(t, err)
+
+	raw, err := os.ReadFile(path)
+	assert.NilError(t, err)
+
+	// Same length as the original, so only the hash can tell them apart.
+	replacement := bytes.Replace(raw, []byte(`"Value": 1,`), []byte(`"Value": 9,`), 1)
+	replacement = bytes.Replace(replacement, []byte(`"test"`), []byte(`"abcd"`), 1)
+	assert.Check(t, cmp.Equal(len(replacement), len(raw)))
+
+	assert.NilError(t, os.WriteFile(path, replacement, 0o600))
+	assert.NilError(t, os.Chtimes(path, info.ModTime(), info.ModTime()))
+
+	data := f.Peek(ctx)
+	assert.Check(t, cmp.Equal(data.Value, 9))
+	assert.Check(t, cmp.Equal(data.Name, "abcd"))
+}
+
+// TestChangeDetectionSizeMtimeHashReloadsOnMtimeAloneAdvance proves that
+// ModeSizeMtimeHash's extra mtime fast-path still triggers a reload even
+// when the size+hash fingerprint is unchanged, unlike plain ModeSizeHash.
+func TestChangeDetectionSizeMtimeHashReloadsOnMtimeAloneAdvance(t *testing.T) {
+	path := testFilePath(t)
+	ctx := context.Background()
+
+	f := New(path, WithDefault(func() TestData {
+		return TestData{Value: 42, Name: "test"}
+	}), WithChangeDetection[TestData](ModeSizeMtimeHash))
+
+	err := f.Update(ctx, func(data *TestData) error {
+		data.Value = 1
+		return nil
+	})
+	assert.NilError(t, err)
+
+	reloaded := false
+	f2 := New(path, WithDefault(func() TestData {
+		return TestData{Value: 42, Name: "test"}
+	}), WithChangeDetection[TestData](ModeSizeMtimeHash),
+		WithLoadedCallback[TestData](func(_ context.Context, _ *TestData) {
+			reloaded = true
+		}))
+	// Prime f2's cache and fingerprint from the current file contents.
+	_ = f2.Peek(ctx)
+	reloaded = false
+
+	// Rewrite the exact same bytes, so size and hash are unchanged, but
+	// bump the mtime forward.
+	raw, err := os.ReadFile(path)
+	assert.NilError(t, err)
+	future := time.Now().Add(time.Hour)
+	assert.NilError(t, os.WriteFile(path, raw, 0o600))
+	assert.NilError(t, os.Chtimes(path, future, future))
+
+	_ = f2.Peek(ctx)
+	assert.Check(t, reloaded)
+}
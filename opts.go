@@ -1,6 +1,9 @@
 package livefile
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type Opt[T any] func(s *LiveFile[T])
 
@@ -31,3 +34,51 @@ func WithLoadedCallback[T any](f func(context.Context, *T)) Opt[T] {
 		s.onLoaded = f
 	}
 }
+
+// WithFileSystem sets the [WriteFS] implementation used for file operations.
+// If not set, [DefaultFileSystem] (the OS filesystem) is used. This allows
+// plugging in e.g. an afero.Fs-backed implementation.
+func WithFileSystem[T any](fs WriteFS) Opt[T] {
+	return func(s *LiveFile[T]) {
+		s.fs = fs
+	}
+}
+
+// WithWriteBack enables write-back (coalescing) mode: a successful [LiveFile.Update]
+// mutator updates the in-memory state immediately but the write to disk is
+// deferred by delay, coalescing any further updates that land within the
+// window into a single JSON encode and [WriteFile.Sync].
+// Use [LiveFile.Flush] to force an immediate write and [LiveFile.Close] to
+// guarantee that any pending state is made durable. A failed background
+// flush is reported through the configured error handler, see [WithErrorHandler] -
+// note that this delivery happens on a timer goroutine the caller never sees,
+// so pairing [WithWriteBack] with [DefaultErrorHandler] (which panics) will
+// crash the whole process instead of just the call that triggered the error.
+// Set an explicit, non-panicking [WithErrorHandler] whenever write-back mode
+// is used.
+// If not set, every successful Update is written to disk synchronously.
+func WithWriteBack[T any](delay time.Duration) Opt[T] {
+	return func(s *LiveFile[T]) {
+		s.writeBackDelay = delay
+	}
+}
+
+// WithChangeDetection sets the [ChangeDetectionMode] used to decide whether
+// the file needs to be reloaded. If not set, [ModeMtime] is used.
+func WithChangeDetection[T any](mode ChangeDetectionMode) Opt[T] {
+	return func(s *LiveFile[T]) {
+		s.changeMode = mode
+	}
+}
+
+// WithAtomicWrite switches the write path to write a temporary file next to
+// the destination, sync it, and [WriteFS.Rename] it over the destination,
+// instead of truncating and streaming JSON into the destination in place.
+// This avoids leaving a half-written, undecodable file behind if the
+// process crashes or the error handler panics mid-encode.
+// If not set, writes go directly into the destination file.
+func WithAtomicWrite[T any](enabled bool) Opt[T] {
+	return func(s *LiveFile[T]) {
+		s.atomicWrite = enabled
+	}
+}
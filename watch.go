@@ -0,0 +1,112 @@
+package livefile
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+)
+
+// watchDebounce coalesces the burst of events a single logical save tends to
+// produce (editors often write a temp file then rename it over the target).
+const watchDebounce = 100 * time.Millisecond
+
+// watchPollInterval is the interval used to poll for changes when the
+// configured [WriteFS] doesn't implement [Watcher].
+const watchPollInterval = time.Second
+
+// Subscribe starts watching the file for external changes and calls f with
+// the reloaded state every time a change is detected. The state pointer
+// passed to f is only valid within the call and must not be stored.
+// The function must not call other [LiveFile] methods.
+//
+// If the configured [WriteFS] implements [Watcher], native notifications are
+// used; otherwise the file is polled using the configured
+// [ChangeDetectionMode] (see [WithChangeDetection]). Either way, the returned
+// unsubscribe function stops the watch, which also happens automatically
+// when ctx is cancelled.
+func (lf *LiveFile[T]) Subscribe(ctx context.Context, f func(context.Context, *T)) (func(), error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	if w, ok := lf.fs.(Watcher); ok {
+		if err := lf.subscribeNotify(ctx, w, f); err != nil {
+			cancel()
+			return nil, err
+		}
+		return cancel, nil
+	}
+
+	lf.subscribePoll(ctx, f)
+	return cancel, nil
+}
+
+func (lf *LiveFile[T]) subscribeNotify(ctx context.Context, w Watcher, f func(context.Context, *T)) error {
+	dir := filepath.Dir(lf.path)
+	base := filepath.Base(lf.path)
+
+	events, stop, err := w.Watch(dir)
+	if err != nil {
+		return err
+	}
+
+	reload := func() {
+		lf.reloadAndNotify(ctx, f)
+	}
+
+	go func() {
+		defer stop()
+
+		var debounce *time.Timer
+		for {
+			select {
+			case <-ctx.Done():
+				if debounce != nil {
+					debounce.Stop()
+				}
+				return
+			case ev, ok := <-events:
+				if !ok {
+					return
+				}
+				if filepath.Base(ev.Name) != base {
+					continue
+				}
+				if debounce == nil {
+					debounce = time.AfterFunc(watchDebounce, reload)
+				} else {
+					debounce.Reset(watchDebounce)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (lf *LiveFile[T]) subscribePoll(ctx context.Context, f func(context.Context, *T)) {
+	go func() {
+		ticker := time.NewTicker(watchPollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				lf.reloadAndNotify(ctx, f)
+			}
+		}
+	}()
+}
+
+// reloadAndNotify re-reads the file and calls f only if ensure() actually
+// reloaded lf.cached, so that an ensure() that found nothing stale (e.g.
+// triggered by the LiveFile's own Update/Flush touching the watched file)
+// doesn't produce a spurious notification with the unchanged value.
+func (lf *LiveFile[T]) reloadAndNotify(ctx context.Context, f func(context.Context, *T)) {
+	lf.mutex.Lock()
+	defer lf.mutex.Unlock()
+
+	if lf.ensure(ctx) {
+		f(ctx, &lf.cached)
+	}
+}
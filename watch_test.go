@@ -0,0 +1,148 @@
+package livefile
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	"gotest.tools/assert/cmp"
+)
+
+// noWatchFS wraps a WriteFS but hides any Watcher capability it might have,
+// forcing LiveFile.Subscribe to fall back to polling.
+type noWatchFS struct {
+	WriteFS
+}
+
+func TestSubscribeNotifyOnExternalChange(t *testing.T) {
+	path := testFilePath(t)
+	ctx := context.Background()
+
+	f := New(path, WithDefault(func() TestData {
+		return TestData{Value: 42, Name: "test"}
+	}))
+
+	received := make(chan TestData, 1)
+	unsubscribe, err := f.Subscribe(ctx, func(_ context.Context, state *TestData) {
+		received <- *state
+	})
+	assert.NilError(t, err)
+	defer unsubscribe()
+
+	assert.NilError(t, f.Update(ctx, func(data *TestData) error {
+		data.Value = 1
+		return nil
+	}))
+
+	assert.NilError(t, os.WriteFile(path, []byte(`{"Value": 99, "Name": "external"}`), 0o600))
+
+	select {
+	case data := <-received:
+		assert.Check(t, cmp.Equal(data.Value, 99))
+		assert.Check(t, cmp.Equal(data.Name, "external"))
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for subscription notification")
+	}
+}
+
+// TestSubscribeIgnoresOwnUpdate ensures that a LiveFile's own Update, which
+// necessarily touches the watched file, doesn't surface as a bogus "external
+// change" notification to a subscriber.
+func TestSubscribeIgnoresOwnUpdate(t *testing.T) {
+	path := testFilePath(t)
+	ctx := context.Background()
+
+	f := New(path, WithDefault(func() TestData {
+		return TestData{Value: 42, Name: "test"}
+	}))
+
+	received := make(chan TestData, 1)
+	unsubscribe, err := f.Subscribe(ctx, func(_ context.Context, state *TestData) {
+		received <- *state
+	})
+	assert.NilError(t, err)
+	defer unsubscribe()
+
+	assert.NilError(t, f.Update(ctx, func(data *TestData) error {
+		data.Value = 1
+		return nil
+	}))
+
+	select {
+	case data := <-received:
+		t.Fatalf("unexpected notification for our own Update: %+v", data)
+	case <-time.After(500 * time.Millisecond):
+	}
+}
+
+// TestSubscribeNotifyOnSizeHashOnlyChange reproduces an external rewrite that
+// lands with the same mtime as the last load (e.g. restored or same-second),
+// where only the [ModeSizeHash] fingerprint can tell the content changed.
+// Subscribers must still be notified, not just lf.cached silently updated.
+func TestSubscribeNotifyOnSizeHashOnlyChange(t *testing.T) {
+	path := testFilePath(t)
+	ctx := context.Background()
+
+	f := New(path, WithDefault(func() TestData {
+		return TestData{Value: 42, Name: "test"}
+	}), WithChangeDetection[TestData](ModeSizeHash))
+	f.fs = noWatchFS{WriteFS: f.fs}
+
+	assert.NilError(t, f.Update(ctx, func(data *TestData) error {
+		data.Value = 1
+		data.Name = "test"
+		return nil
+	}))
+
+	info, err := os.Stat(path)
+	assert.NilError(t, err)
+
+	received := make(chan TestData, 1)
+	unsubscribe, err := f.Subscribe(ctx, func(_ context.Context, state *TestData) {
+		received <- *state
+	})
+	assert.NilError(t, err)
+	defer unsubscribe()
+
+	// Same length as the original, so only the hash can tell them apart, and
+	// the mtime is restored to the original value.
+	assert.NilError(t, os.WriteFile(path, []byte("{\n  \"Value\": 9,\n  \"Name\": \"abcd\"\n}\n"), 0o600))
+	assert.NilError(t, os.Chtimes(path, info.ModTime(), info.ModTime()))
+
+	select {
+	case data := <-received:
+		assert.Check(t, cmp.Equal(data.Value, 9))
+		assert.Check(t, cmp.Equal(data.Name, "abcd"))
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for subscription notification on a size+hash-only change")
+	}
+}
+
+func TestSubscribePollFallback(t *testing.T) {
+	path := testFilePath(t)
+	ctx := context.Background()
+
+	f := New(path, WithDefault(func() TestData {
+		return TestData{Value: 42, Name: "test"}
+	}))
+	f.fs = noWatchFS{WriteFS: f.fs}
+
+	received := make(chan TestData, 1)
+	unsubscribe, err := f.Subscribe(ctx, func(_ context.Context, state *TestData) {
+		received <- *state
+	})
+	assert.NilError(t, err)
+	defer unsubscribe()
+
+	assert.NilError(t, os.WriteFile(path, []byte(`{"Value": 77, "Name": "polled"}`), 0o600))
+
+	select {
+	case data := <-received:
+		assert.Check(t, cmp.Equal(data.Value, 77))
+		assert.Check(t, cmp.Equal(data.Name, "polled"))
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for poll notification")
+	}
+}